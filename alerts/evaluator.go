@@ -0,0 +1,120 @@
+package alerts
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// FiringAlert describes a rule+scheme combination that is currently in the
+// firing state, as reported by GET /api/alerts and the Cron response.
+type FiringAlert struct {
+	Rule       string    `json:"rule"`
+	SchemeCode string    `json:"scheme_code"`
+	Value      float64   `json:"value"`
+	FiredAt    time.Time `json:"firedAt"`
+}
+
+// Evaluate loads the configured rules, fetches NAV data for every unique
+// scheme code referenced, evaluates each rule's expression, advances the
+// pending/firing state machine, and notifies receivers of newly-firing
+// alerts. It returns every alert currently in the firing state.
+func Evaluate() ([]FiringAlert, error) {
+	rules, err := LoadRules()
+	if err != nil {
+		return nil, fmt.Errorf("loading alert rules: %w", err)
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	states, err := loadState()
+	if err != nil {
+		return nil, fmt.Errorf("loading alert state: %w", err)
+	}
+
+	seriesCache := make(map[string][]sample)
+	now := time.Now()
+
+	for _, rule := range rules {
+		samples, ok := seriesCache[rule.SchemeCode]
+		if !ok {
+			samples, err = fetchSamples(rule.SchemeCode)
+			if err != nil {
+				slog.Error("alerts: skipping rule", "rule", rule.Name, "error", err)
+				continue
+			}
+			seriesCache[rule.SchemeCode] = samples
+		}
+
+		value, fires, err := evalExpr(rule.Expr, samples)
+		if err != nil {
+			slog.Error("alerts: skipping rule", "rule", rule.Name, "error", err)
+			continue
+		}
+
+		key := stateKey(rule.Name, rule.SchemeCode)
+		st, exists := states[key]
+
+		if !fires {
+			delete(states, key)
+			continue
+		}
+
+		if !exists {
+			st = &ruleState{RuleName: rule.Name, SchemeCode: rule.SchemeCode, PendingSince: now}
+			states[key] = st
+		}
+		st.Value = value
+
+		if !st.Firing && now.Sub(st.PendingSince) >= rule.For {
+			st.Firing = true
+			st.FiredAt = now
+			notify(rule.Receivers, firedPayload{
+				Rule:       rule.Name,
+				SchemeCode: rule.SchemeCode,
+				Value:      value,
+				FiredAt:    now,
+			})
+		}
+	}
+
+	if err := saveState(states); err != nil {
+		return nil, fmt.Errorf("saving alert state: %w", err)
+	}
+
+	var firing []FiringAlert
+	for _, st := range states {
+		if st.Firing {
+			firing = append(firing, FiringAlert{
+				Rule:       st.RuleName,
+				SchemeCode: st.SchemeCode,
+				Value:      st.Value,
+				FiredAt:    st.FiredAt,
+			})
+		}
+	}
+	return firing, nil
+}
+
+// CurrentlyFiring returns the alerts presently in the firing state without
+// re-evaluating rules, for use by read-only endpoints such as GET /api/alerts.
+func CurrentlyFiring() ([]FiringAlert, error) {
+	states, err := loadState()
+	if err != nil {
+		return nil, fmt.Errorf("loading alert state: %w", err)
+	}
+
+	var firing []FiringAlert
+	for _, st := range states {
+		if st.Firing {
+			firing = append(firing, FiringAlert{
+				Rule:       st.RuleName,
+				SchemeCode: st.SchemeCode,
+				Value:      st.Value,
+				FiredAt:    st.FiredAt,
+			})
+		}
+	}
+	return firing, nil
+}
@@ -0,0 +1,39 @@
+package alerts
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+const mfapiBaseURL = "https://api.mfapi.in/mf/"
+
+type mfapiResponse struct {
+	Data []struct {
+		Date string `json:"date"`
+		Nav  string `json:"nav"`
+	} `json:"data"`
+}
+
+// fetchSamples retrieves and parses the NAV history for a scheme code.
+func fetchSamples(schemeCode string) ([]sample, error) {
+	resp, err := http.Get(mfapiBaseURL + schemeCode)
+	if err != nil {
+		return nil, fmt.Errorf("fetching NAV data for %s: %w", schemeCode, err)
+	}
+	defer resp.Body.Close()
+
+	var parsed mfapiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("decoding NAV data for %s: %w", schemeCode, err)
+	}
+
+	dates := make([]string, len(parsed.Data))
+	navs := make([]string, len(parsed.Data))
+	for i, d := range parsed.Data {
+		dates[i] = d.Date
+		navs[i] = d.Nav
+	}
+
+	return samplesFromDataPoints(dates, navs), nil
+}
@@ -0,0 +1,152 @@
+package alerts
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// sample is a parsed, chronologically ordered NAV observation.
+type sample struct {
+	date time.Time
+	nav  float64
+}
+
+// samplesFromDataPoints converts raw dd-mm-yyyy/string NAV pairs into sorted
+// samples, discarding any entries that fail to parse.
+func samplesFromDataPoints(dates, navs []string) []sample {
+	samples := make([]sample, 0, len(dates))
+	for i, d := range dates {
+		t, err := time.Parse("02-01-2006", d)
+		if err != nil {
+			continue
+		}
+		v, err := strconv.ParseFloat(navs[i], 64)
+		if err != nil {
+			continue
+		}
+		samples = append(samples, sample{date: t, nav: v})
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i].date.Before(samples[j].date) })
+	return samples
+}
+
+// indicatorExpr matches expressions of the form "<indicator> <op> <value>",
+// e.g. "drawdown_30d > 0.1" or "nav < 100".
+var indicatorExpr = regexp.MustCompile(`^\s*([a-zA-Z0-9_]+)\s*(>=|<=|==|!=|>|<)\s*(-?[0-9.]+)\s*$`)
+
+// evalExpr evaluates a rule expression against a sample series, returning
+// the indicator's current value and whether the comparison holds.
+func evalExpr(expr string, samples []sample) (value float64, fires bool, err error) {
+	m := indicatorExpr.FindStringSubmatch(expr)
+	if m == nil {
+		return 0, false, fmt.Errorf("invalid expression %q", expr)
+	}
+	indicator, op, thresholdStr := m[1], m[2], m[3]
+
+	threshold, err := strconv.ParseFloat(thresholdStr, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid threshold in expression %q", expr)
+	}
+
+	value, err = computeIndicator(indicator, samples)
+	if err != nil {
+		return 0, false, err
+	}
+
+	switch op {
+	case ">":
+		fires = value > threshold
+	case "<":
+		fires = value < threshold
+	case ">=":
+		fires = value >= threshold
+	case "<=":
+		fires = value <= threshold
+	case "==":
+		fires = value == threshold
+	case "!=":
+		fires = value != threshold
+	}
+
+	return value, fires, nil
+}
+
+var windowedIndicator = regexp.MustCompile(`^(pct_change|drawdown|sma|ema)_(\d+)d$`)
+
+// computeIndicator derives a single named indicator from the sample series.
+// Supported indicators: nav, pct_change_Nd, drawdown_Nd, sma_Nd, ema_Nd.
+func computeIndicator(name string, samples []sample) (float64, error) {
+	if len(samples) == 0 {
+		return 0, fmt.Errorf("no NAV data available")
+	}
+
+	if name == "nav" {
+		return samples[len(samples)-1].nav, nil
+	}
+
+	m := windowedIndicator.FindStringSubmatch(name)
+	if m == nil {
+		return 0, fmt.Errorf("unknown indicator %q", name)
+	}
+	kind := m[1]
+	days, _ := strconv.Atoi(m[2])
+	window := lastNDays(samples, days)
+	if len(window) == 0 {
+		return 0, fmt.Errorf("not enough NAV data for indicator %q", name)
+	}
+
+	switch kind {
+	case "pct_change":
+		first, last := window[0].nav, window[len(window)-1].nav
+		if first == 0 {
+			return 0, fmt.Errorf("cannot compute pct_change: first NAV in window is zero")
+		}
+		return (last - first) / first, nil
+	case "drawdown":
+		peak := window[0].nav
+		for _, s := range window {
+			if s.nav > peak {
+				peak = s.nav
+			}
+		}
+		last := window[len(window)-1].nav
+		if peak == 0 {
+			return 0, nil
+		}
+		return (peak - last) / peak, nil
+	case "sma":
+		var sum float64
+		for _, s := range window {
+			sum += s.nav
+		}
+		return sum / float64(len(window)), nil
+	case "ema":
+		alpha := 2.0 / float64(len(window)+1)
+		ema := window[0].nav
+		for _, s := range window[1:] {
+			ema = alpha*s.nav + (1-alpha)*ema
+		}
+		return ema, nil
+	}
+
+	return 0, fmt.Errorf("unknown indicator kind %q", kind)
+}
+
+// lastNDays returns the samples falling within the last N days counting back
+// from the most recent sample in the series.
+func lastNDays(samples []sample, days int) []sample {
+	if len(samples) == 0 {
+		return nil
+	}
+	cutoff := samples[len(samples)-1].date.AddDate(0, 0, -days)
+	var window []sample
+	for _, s := range samples {
+		if s.date.After(cutoff) || s.date.Equal(cutoff) {
+			window = append(window, s)
+		}
+	}
+	return window
+}
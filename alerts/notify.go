@@ -0,0 +1,93 @@
+package alerts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+)
+
+// firedPayload is the JSON body POSTed to webhook receivers when a rule
+// transitions into the firing state.
+type firedPayload struct {
+	Rule       string    `json:"rule"`
+	SchemeCode string    `json:"scheme_code"`
+	Value      float64   `json:"value"`
+	FiredAt    time.Time `json:"firedAt"`
+}
+
+const (
+	notifyMaxAttempts = 4
+	notifyBaseBackoff = 500 * time.Millisecond
+)
+
+// notify dispatches the firing payload to every configured receiver.
+// Webhook receivers (http/https URLs) are POSTed with retry and exponential
+// backoff; "mailto:" receivers are sent via SMTP using the SMTP_* env vars.
+func notify(receivers []string, payload firedPayload) {
+	for _, receiver := range receivers {
+		if strings.HasPrefix(receiver, "mailto:") {
+			if err := sendEmail(strings.TrimPrefix(receiver, "mailto:"), payload); err != nil {
+				fmt.Fprintf(os.Stderr, "alerts: failed to email %s: %v\n", receiver, err)
+			}
+			continue
+		}
+		if err := postWebhook(receiver, payload); err != nil {
+			fmt.Fprintf(os.Stderr, "alerts: failed to notify %s: %v\n", receiver, err)
+		}
+	}
+}
+
+func postWebhook(url string, payload firedPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < notifyMaxAttempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(notifyBaseBackoff * time.Duration(1<<uint(attempt-1)))
+		}
+
+		resp, err := http.Post(url, "application/json", bytes.NewReader(body))
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return nil
+		}
+		lastErr = fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return lastErr
+}
+
+func sendEmail(to string, payload firedPayload) error {
+	host := os.Getenv("SMTP_HOST")
+	port := os.Getenv("SMTP_PORT")
+	user := os.Getenv("SMTP_USER")
+	pass := os.Getenv("SMTP_PASSWORD")
+	from := os.Getenv("SMTP_FROM")
+	if host == "" || port == "" || from == "" {
+		return fmt.Errorf("SMTP_HOST, SMTP_PORT, and SMTP_FROM must be set to send alert emails")
+	}
+
+	subject := fmt.Sprintf("[ALERT] %s firing for scheme %s", payload.Rule, payload.SchemeCode)
+	body := fmt.Sprintf("Rule %q fired for scheme_code %s with value %f at %s.",
+		payload.Rule, payload.SchemeCode, payload.Value, payload.FiredAt.Format(time.RFC3339))
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s\r\n", from, to, subject, body)
+
+	var auth smtp.Auth
+	if user != "" {
+		auth = smtp.PlainAuth("", user, pass, host)
+	}
+
+	return smtp.SendMail(host+":"+port, auth, from, []string{to}, []byte(msg))
+}
@@ -0,0 +1,67 @@
+// Package alerts evaluates user-defined threshold rules against mutual fund
+// NAV history and dispatches notifications when a rule has been sustained
+// for its configured duration, mirroring Prometheus' alerting model.
+package alerts
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Rule describes a single alerting rule loaded from the rules file.
+type Rule struct {
+	Name       string        `json:"name" yaml:"name"`
+	SchemeCode string        `json:"scheme_code" yaml:"scheme_code"`
+	Expr       string        `json:"expr" yaml:"expr"`
+	For        time.Duration `json:"-" yaml:"-"`
+	ForRaw     string        `json:"for" yaml:"for"`
+	Receivers  []string      `json:"receivers" yaml:"receivers"`
+}
+
+// LoadRules reads the rules file referenced by the ALERTS_RULES_PATH
+// environment variable. The format (YAML or JSON) is inferred from the file
+// extension; an empty path or a file that does not exist yields no rules so
+// the Cron handler can run with alerting disabled by default.
+func LoadRules() ([]Rule, error) {
+	path := os.Getenv("ALERTS_RULES_PATH")
+	if path == "" {
+		return nil, nil
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading rules file: %w", err)
+	}
+
+	var rules []Rule
+	if strings.HasSuffix(path, ".yaml") || strings.HasSuffix(path, ".yml") {
+		if err := yaml.Unmarshal(raw, &rules); err != nil {
+			return nil, fmt.Errorf("parsing YAML rules file: %w", err)
+		}
+	} else {
+		if err := json.Unmarshal(raw, &rules); err != nil {
+			return nil, fmt.Errorf("parsing JSON rules file: %w", err)
+		}
+	}
+
+	for i := range rules {
+		if rules[i].ForRaw == "" {
+			continue
+		}
+		dur, err := time.ParseDuration(rules[i].ForRaw)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: invalid for duration %q: %w", rules[i].Name, rules[i].ForRaw, err)
+		}
+		rules[i].For = dur
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,60 @@
+package alerts
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+)
+
+// defaultStatePath is used when ALERTS_STATE_PATH is unset. /tmp is the only
+// writable directory on Vercel's Go runtime, so state only survives within a
+// warm container, not across cold starts.
+const defaultStatePath = "/tmp/go-vercel-alerts-state.json"
+
+// ruleState tracks the pending/firing lifecycle of a single rule+scheme
+// combination so the "for" clause can require a sustained breach.
+type ruleState struct {
+	RuleName     string    `json:"rule_name"`
+	SchemeCode   string    `json:"scheme_code"`
+	Value        float64   `json:"value"`
+	PendingSince time.Time `json:"pending_since"`
+	Firing       bool      `json:"firing"`
+	FiredAt      time.Time `json:"fired_at,omitempty"`
+}
+
+func stateKey(ruleName, schemeCode string) string {
+	return ruleName + "|" + schemeCode
+}
+
+func statePath() string {
+	if path := os.Getenv("ALERTS_STATE_PATH"); path != "" {
+		return path
+	}
+	return defaultStatePath
+}
+
+// loadState reads the persisted rule state from disk. A missing file is not
+// an error: it just means every rule starts out clear.
+func loadState() (map[string]*ruleState, error) {
+	raw, err := os.ReadFile(statePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return make(map[string]*ruleState), nil
+		}
+		return nil, err
+	}
+
+	states := make(map[string]*ruleState)
+	if err := json.Unmarshal(raw, &states); err != nil {
+		return nil, err
+	}
+	return states, nil
+}
+
+func saveState(states map[string]*ruleState) error {
+	raw, err := json.Marshal(states)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(statePath(), raw, 0o644)
+}
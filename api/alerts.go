@@ -0,0 +1,24 @@
+package mutualfunds
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Ankitz007/go-vercel/alerts"
+)
+
+// Alerts reports every alert rule currently in the firing state, analogous
+// to Prometheus' GET /api/v1/alerts endpoint. It does not evaluate rules
+// itself - evaluation happens on each Cron invocation.
+func Alerts(w http.ResponseWriter, r *http.Request) {
+	firing, err := alerts.CurrentlyFiring()
+	if err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": firing,
+	})
+}
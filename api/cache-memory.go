@@ -0,0 +1,56 @@
+package mutualfunds
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// memoryCacheEntry pairs a fetched Fund with the time it was fetched, so
+// callers can compute its age against the configured TTL.
+type memoryCacheEntry struct {
+	fund      Fund
+	fetchedAt time.Time
+}
+
+// memoryCache is a process-local Cache backed by a mutex-protected map. It
+// only survives for the lifetime of a warm container; see redisCache for a
+// backend that survives cold starts.
+type memoryCache struct {
+	mu      sync.RWMutex
+	entries map[string]memoryCacheEntry
+}
+
+func newMemoryCache() *memoryCache {
+	return &memoryCache{entries: make(map[string]memoryCacheEntry)}
+}
+
+func (c *memoryCache) Get(_ context.Context, key string) (Fund, time.Time, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return Fund{}, time.Time{}, false
+	}
+	return entry.fund, entry.fetchedAt, true
+}
+
+func (c *memoryCache) Set(_ context.Context, key string, fund Fund, fetchedAt time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = memoryCacheEntry{fund: fund, fetchedAt: fetchedAt}
+	return nil
+}
+
+func (c *memoryCache) Delete(_ context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+	return nil
+}
+
+func (c *memoryCache) Len(_ context.Context) (int, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.entries), nil
+}
@@ -0,0 +1,70 @@
+package mutualfunds
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisCacheKeyPrefix namespaces our keys within a shared Redis instance.
+const redisCacheKeyPrefix = "go-vercel:mf-cache:"
+
+// redisEntry is the JSON shape persisted in Redis, keeping the fetch
+// timestamp alongside the Fund payload so staleness can be computed the
+// same way as memoryCache.
+type redisEntry struct {
+	Fund      Fund      `json:"fund"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// redisCache is a Cache backed by Redis, used when REDIS_URL is set so
+// cached NAV data survives across Vercel cold starts.
+type redisCache struct {
+	client *redis.Client
+}
+
+func newRedisCache(redisURL string) (*redisCache, error) {
+	opts, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing REDIS_URL: %w", err)
+	}
+	return &redisCache{client: redis.NewClient(opts)}, nil
+}
+
+func (c *redisCache) Get(ctx context.Context, key string) (Fund, time.Time, bool) {
+	raw, err := c.client.Get(ctx, redisCacheKeyPrefix+key).Bytes()
+	if err != nil {
+		return Fund{}, time.Time{}, false
+	}
+
+	var entry redisEntry
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return Fund{}, time.Time{}, false
+	}
+	return entry.Fund, entry.FetchedAt, true
+}
+
+func (c *redisCache) Set(ctx context.Context, key string, fund Fund, fetchedAt time.Time) error {
+	raw, err := json.Marshal(redisEntry{Fund: fund, FetchedAt: fetchedAt})
+	if err != nil {
+		return err
+	}
+	// Keep entries around for a couple of TTL windows so stale-while-
+	// revalidate reads still find something to serve.
+	return c.client.Set(ctx, redisCacheKeyPrefix+key, raw, 2*cacheTTL()).Err()
+}
+
+func (c *redisCache) Delete(ctx context.Context, key string) error {
+	return c.client.Del(ctx, redisCacheKeyPrefix+key).Err()
+}
+
+func (c *redisCache) Len(ctx context.Context) (int, error) {
+	keys, err := c.client.Keys(ctx, redisCacheKeyPrefix+"*").Result()
+	if err != nil {
+		return 0, err
+	}
+	return len(keys), nil
+}
@@ -0,0 +1,168 @@
+package mutualfunds
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Ankitz007/go-vercel/observability"
+)
+
+// Cache abstracts storage for fetched Fund data so the in-process map used
+// during local development / a single warm Lambda container can be swapped
+// for a shared backend (Redis) that survives cold starts.
+type Cache interface {
+	Get(ctx context.Context, key string) (fund Fund, fetchedAt time.Time, ok bool)
+	Set(ctx context.Context, key string, fund Fund, fetchedAt time.Time) error
+	Delete(ctx context.Context, key string) error
+	Len(ctx context.Context) (int, error)
+}
+
+// cacheTTL returns the configured cache TTL (env MF_CACHE_TTL), defaulting
+// to 6 hours. Entries are served stale for up to one additional TTL while a
+// background refresh runs.
+func cacheTTL() time.Duration {
+	if raw := os.Getenv("MF_CACHE_TTL"); raw != "" {
+		if d, err := time.ParseDuration(raw); err == nil && d > 0 {
+			return d
+		}
+	}
+	return 6 * time.Hour
+}
+
+// cacheStats are exposed via GET /api/cache/stats.
+var cacheStats = struct {
+	Hits          atomic.Int64
+	Misses        atomic.Int64
+	StaleServes   atomic.Int64
+	BytesEstimate atomic.Int64
+}{}
+
+var (
+	cacheOnce sync.Once
+	cache     Cache
+	inflight  sync.Map // mutualFundID -> struct{}, guards background refreshes
+)
+
+// getCache lazily builds the package-level Cache: a Redis-backed cache when
+// REDIS_URL is set, otherwise an in-memory map scoped to the warm container.
+func getCache() Cache {
+	cacheOnce.Do(func() {
+		if url := os.Getenv("REDIS_URL"); url != "" {
+			if redisCache, err := newRedisCache(url); err == nil {
+				cache = redisCache
+				return
+			}
+		}
+		cache = newMemoryCache()
+	})
+	return cache
+}
+
+// getCachedFund serves Fund data for mutualFundID from cache when possible.
+// A fresh hit (age < TTL) is served directly. A stale hit (TTL <= age <
+// 2*TTL) is served immediately while a background goroutine refreshes the
+// entry, deduplicated per ID via inflight. Anything older, or a miss,
+// fetches synchronously from upstream.
+func getCachedFund(ctx context.Context, mutualFundID string) (Fund, error) {
+	ttl := cacheTTL()
+	c := getCache()
+
+	if fund, fetchedAt, ok := c.Get(ctx, mutualFundID); ok {
+		age := time.Since(fetchedAt)
+		if age < ttl {
+			cacheStats.Hits.Add(1)
+			observability.CacheEventsTotal.WithLabelValues("hit").Inc()
+			return fund, nil
+		}
+		if age < 2*ttl {
+			cacheStats.StaleServes.Add(1)
+			observability.CacheEventsTotal.WithLabelValues("stale_serve").Inc()
+			triggerRefresh(mutualFundID)
+			return fund, nil
+		}
+	}
+
+	cacheStats.Misses.Add(1)
+	observability.CacheEventsTotal.WithLabelValues("miss").Inc()
+	fund, err := fetchFundData(ctx, mutualFundID)
+	if err != nil {
+		return Fund{}, err
+	}
+	// An empty Meta means mutualFundID was not recognized by mfapi.in; don't
+	// cache a negative result for a full TTL window.
+	if isEmptyMeta(fund.Meta) {
+		return fund, nil
+	}
+	if err := c.Set(ctx, mutualFundID, fund, time.Now()); err == nil {
+		if raw, marshalErr := json.Marshal(fund); marshalErr == nil {
+			cacheStats.BytesEstimate.Add(int64(len(raw)))
+		}
+	}
+	return fund, nil
+}
+
+// triggerRefresh kicks off a background refetch for mutualFundID unless one
+// is already in flight.
+func triggerRefresh(mutualFundID string) {
+	if _, loaded := inflight.LoadOrStore(mutualFundID, struct{}{}); loaded {
+		return
+	}
+	go func() {
+		defer inflight.Delete(mutualFundID)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		fund, err := fetchFundData(ctx, mutualFundID)
+		if err != nil {
+			return
+		}
+		getCache().Set(ctx, mutualFundID, fund, time.Now())
+	}()
+}
+
+// CacheStatsHandler serves GET /api/cache/stats for observability, and
+// DELETE /api/cache?mutualFundID=... to evict an entry. Both are protected
+// by the same CRON_SECRET bearer auth used by handler.Cron.
+func CacheStatsHandler(w http.ResponseWriter, r *http.Request) {
+	authHeader := r.Header.Get("Authorization")
+	cronSecret := os.Getenv("CRON_SECRET")
+	if authHeader != "Bearer "+cronSecret {
+		createErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodDelete:
+		mutualFundID := r.URL.Query().Get("mutualFundID")
+		if mutualFundID == "" {
+			createErrorResponse(w, http.StatusBadRequest, "mutualFundID query parameter is required")
+			return
+		}
+		if err := getCache().Delete(r.Context(), mutualFundID); err != nil {
+			createErrorResponse(w, http.StatusInternalServerError, fmt.Sprintf("error evicting cache entry: %s", err))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		entries, err := getCache().Len(r.Context())
+		if err != nil {
+			createErrorResponse(w, http.StatusInternalServerError, fmt.Sprintf("error reading cache size: %s", err))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]int64{
+			"entries":        int64(entries),
+			"hits":           cacheStats.Hits.Load(),
+			"misses":         cacheStats.Misses.Load(),
+			"stale_serves":   cacheStats.StaleServes.Load(),
+			"bytes_estimate": cacheStats.BytesEstimate.Load(),
+		})
+	}
+}
@@ -1,12 +1,23 @@
-package handler
+package mutualfunds
 
 import (
 	"encoding/json"
 	"net/http"
 	"os"
+
+	"github.com/Ankitz007/go-vercel/alerts"
+	"github.com/Ankitz007/go-vercel/observability"
 )
 
+// Cron is the /api/cron entrypoint, wrapped with request metrics and
+// structured logging. It must stay a func (not a var of func type) so
+// Vercel's Go runtime can discover it by scanning for an exported handler
+// function.
 func Cron(w http.ResponseWriter, r *http.Request) {
+	observability.WithObservability("cron", handleCron)(w, r)
+}
+
+func handleCron(w http.ResponseWriter, r *http.Request) {
 	authHeader := r.Header.Get("Authorization")
 	cronSecret := os.Getenv("CRON_SECRET")
 
@@ -15,8 +26,15 @@ func Cron(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response := map[string]string{
-		"data": "Hello, Cron!",
+	firing, err := alerts.Evaluate()
+	if err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]any{
+		"data":   "Hello, Cron!",
+		"alerts": firing,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
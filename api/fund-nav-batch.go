@@ -0,0 +1,134 @@
+package mutualfunds
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/Ankitz007/go-vercel/observability"
+)
+
+// maxBatchSchemes caps how many scheme codes a single batch request may ask
+// for, bounding worst-case fan-out to the upstream API.
+const maxBatchSchemes = 25
+
+// batchFetchGroup coalesces concurrent fetches for the same scheme code,
+// whether they originate from the same batch request or from independent
+// requests arriving at the same time.
+var batchFetchGroup singleflight.Group
+
+type batchResponse struct {
+	Results map[string]Response `json:"results"`
+	Errors  map[string]string   `json:"errors,omitempty"`
+}
+
+// BatchHandler is the /api/fund-nav-batch entrypoint, wrapped with request
+// metrics and structured logging. It must stay a func (not a var of func
+// type) so Vercel's Go runtime can discover it by scanning for an exported
+// handler function.
+func BatchHandler(w http.ResponseWriter, r *http.Request) {
+	observability.WithObservability("fund-nav-batch", handleBatch)(w, r)
+}
+
+// handleBatch fetches NAV history for multiple scheme codes in one round
+// trip, fanning out fetchFundData calls across a bounded worker pool and
+// reporting per-scheme errors independently instead of failing the whole
+// request.
+func handleBatch(w http.ResponseWriter, r *http.Request) {
+	idsParam := r.URL.Query().Get("mutualFundIDs")
+	if idsParam == "" {
+		createErrorResponse(w, http.StatusBadRequest, "mutualFundIDs query parameter is required")
+		return
+	}
+
+	ids := strings.Split(idsParam, ",")
+	if len(ids) > maxBatchSchemes {
+		createErrorResponse(w, http.StatusBadRequest, "too many mutualFundIDs, max is "+strconv.Itoa(maxBatchSchemes))
+		return
+	}
+	for _, id := range ids {
+		if !isValidInteger(id) {
+			createErrorResponse(w, http.StatusBadRequest, "mutualFundIDs must be a comma-separated list of integers")
+			return
+		}
+	}
+
+	start, end, err := validateAndParseDates(r.URL.Query().Get("start"), r.URL.Query().Get("end"))
+	if err != nil {
+		createErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 25*time.Second)
+	defer cancel()
+
+	results := make(map[string]Response)
+	errs := make(map[string]string)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	jobs := make(chan string)
+	for i := 0; i < batchWorkerCount(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range jobs {
+				response, err := fetchAndFilter(ctx, id, start, end)
+
+				mu.Lock()
+				if err != nil {
+					errs[id] = err.Error()
+				} else {
+					results[id] = response
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, id := range ids {
+		jobs <- id
+	}
+	close(jobs)
+	wg.Wait()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(batchResponse{Results: results, Errors: errs})
+}
+
+// batchWorkerCount reads MF_BATCH_WORKERS, defaulting to 5 workers.
+func batchWorkerCount() int {
+	if raw := os.Getenv("MF_BATCH_WORKERS"); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil && n > 0 {
+			return n
+		}
+	}
+	return 5
+}
+
+// fetchAndFilter fetches a single scheme's NAV history (deduplicated via
+// batchFetchGroup) and applies the requested date filter.
+func fetchAndFilter(ctx context.Context, mutualFundID string, start, end time.Time) (Response, error) {
+	fundAny, err, _ := batchFetchGroup.Do(mutualFundID, func() (interface{}, error) {
+		return getCachedFund(ctx, mutualFundID)
+	})
+	if err != nil {
+		return Response{}, err
+	}
+
+	fund := fundAny.(Fund)
+	if isEmptyMeta(fund.Meta) {
+		return Response{}, errors.New("invalid mutualFundID")
+	}
+
+	page, _ := collectPage(fund.Data, start, end, time.Time{}, 0)
+	return createSuccessResponse(fund.Meta, page, start, end), nil
+}
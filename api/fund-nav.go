@@ -1,16 +1,33 @@
 package mutualfunds
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
 	"time"
+
+	"github.com/Ankitz007/go-vercel/observability"
 )
 
 // Define the base URL as a constant
 const baseURL = "https://api.mfapi.in/mf/"
 
+// upstreamClient is shared across requests so keep-alive connections to
+// mfapi.in are reused instead of being re-established on every call.
+var upstreamClient = &http.Client{
+	Timeout: 10 * time.Second,
+	Transport: &http.Transport{
+		MaxIdleConns:        50,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+	},
+}
+
 // Define a Fund struct to match the API response structure
 type Fund struct {
 	Meta struct {
@@ -45,8 +62,16 @@ type DataPoint struct {
 	Nav  string `json:"nav"`
 }
 
-// HTTP handler function to process the request
+// Handler is the /api/fund-nav entrypoint, wrapped with request metrics and
+// structured logging. It must stay a func (not a var of func type) so
+// Vercel's Go runtime can discover it by scanning for an exported handler
+// function.
 func Handler(w http.ResponseWriter, r *http.Request) {
+	observability.WithObservability("fund-nav", handleFundNav)(w, r)
+}
+
+// handleFundNav processes a single-scheme NAV request.
+func handleFundNav(w http.ResponseWriter, r *http.Request) {
 	// Fetch query parameters
 	mutualFundID := r.URL.Query().Get("mutualFundID")
 	startDate := r.URL.Query().Get("start")
@@ -70,41 +95,313 @@ func Handler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Fetch fund data from API
-	fund, err := fetchFundData(mutualFundID)
+	fund, err := getCachedFund(r.Context(), mutualFundID)
 	if err != nil {
 		createErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	// Check if the meta field is empty, indicating an invalid mutualFundID
-	if fund.Meta == (struct {
-		FundHouse      string `json:"fund_house"`
-		SchemeType     string `json:"scheme_type"`
-		SchemeCategory string `json:"scheme_category"`
-		SchemeCode     int    `json:"scheme_code"`
-		SchemeName     string `json:"scheme_name"`
-	}{}) {
+	if isEmptyMeta(fund.Meta) {
 		createErrorResponse(w, http.StatusBadRequest, "Invalid mutualFundID")
 		return
 	}
 
-	// Filter data based on date range
-	filteredData := filterData(fund.Data, start, end)
+	// mfapi.in returns rows newest-first; keyset pagination below assumes
+	// ascending order so cursors advance towards newer data instead of
+	// re-selecting the same page.
+	sortDataAscending(fund.Data)
 
-	// Create a success response
-	response := createSuccessResponse(fund.Meta, filteredData, start, end)
+	// Parse output format and pagination parameters
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "ndjson" && format != "csv" {
+		createErrorResponse(w, http.StatusBadRequest, "format must be one of json, ndjson, csv")
+		return
+	}
 
-	// Marshal the response to JSON
-	jsonResponse, err := json.Marshal(response)
+	limit, cursor, err := parsePagination(r)
 	if err != nil {
-		createErrorResponse(w, http.StatusInternalServerError, "error creating JSON response")
+		createErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
+	stream := r.URL.Query().Get("stream") == "true"
+
+	switch format {
+	case "ndjson":
+		writeNDJSON(w, r, fund.Data, start, end, cursor, limit, stream)
+	case "csv":
+		writeCSV(w, r, fund.Data, start, end, cursor, limit, stream)
+	default:
+		writeJSON(w, r, fund.Meta, fund.Data, start, end, cursor, limit, stream)
+	}
+}
+
+// parsePagination extracts and validates the limit and cursor query
+// parameters used for keyset pagination. cursor is a base64-encoded
+// dd-mm-yyyy date: only rows strictly after it are returned.
+func parsePagination(r *http.Request) (limit int, cursor time.Time, err error) {
+	if limitParam := r.URL.Query().Get("limit"); limitParam != "" {
+		limit, err = strconv.Atoi(limitParam)
+		if err != nil || limit <= 0 {
+			return 0, time.Time{}, fmt.Errorf("limit must be a positive integer")
+		}
+	}
+
+	if cursorParam := r.URL.Query().Get("cursor"); cursorParam != "" {
+		decoded, decodeErr := base64.URLEncoding.DecodeString(cursorParam)
+		if decodeErr != nil {
+			return 0, time.Time{}, fmt.Errorf("invalid cursor")
+		}
+		cursor, err = time.Parse("02-01-2006", string(decoded))
+		if err != nil {
+			return 0, time.Time{}, fmt.Errorf("invalid cursor")
+		}
+	}
+
+	return limit, cursor, nil
+}
+
+// sortDataAscending sorts NAV data points in place by parsed date, oldest
+// first. Rows with an unparseable date compare as equal to every other row,
+// so where they end up is not defined; iterateFilteredData skips them
+// regardless.
+func sortDataAscending(data []struct {
+	Date string `json:"date"`
+	Nav  string `json:"nav"`
+}) {
+	sort.Slice(data, func(i, j int) bool {
+		di, erri := time.Parse("02-01-2006", data[i].Date)
+		dj, errj := time.Parse("02-01-2006", data[j].Date)
+		if erri != nil || errj != nil {
+			return false
+		}
+		return di.Before(dj)
+	})
+}
+
+// iterateFilteredData returns a range-over-func iterator that yields every
+// DataPoint within [start, end] without allocating the full filtered slice
+// up front, so callers can stop early once a page limit is reached.
+func iterateFilteredData(data []struct {
+	Date string `json:"date"`
+	Nav  string `json:"nav"`
+}, start, end time.Time) func(yield func(DataPoint) bool) {
+	return func(yield func(DataPoint) bool) {
+		for _, item := range data {
+			date, err := time.Parse("02-01-2006", item.Date)
+			if err != nil {
+				continue
+			}
+			if !((start.IsZero() && end.IsZero()) || (date.Equal(start) || date.After(start)) && (date.Equal(end) || date.Before(end))) {
+				continue
+			}
+			if !yield(DataPoint{Date: item.Date, Nav: item.Nav}) {
+				return
+			}
+		}
+	}
+}
+
+// collectPage pulls up to limit rows (0 means unlimited) strictly after
+// cursor from the filtered iterator, reporting whether further rows remain.
+func collectPage(data []struct {
+	Date string `json:"date"`
+	Nav  string `json:"nav"`
+}, start, end, cursor time.Time, limit int) (page []DataPoint, hasMore bool) {
+	iterateFilteredData(data, start, end)(func(dp DataPoint) bool {
+		if !cursor.IsZero() {
+			if d, err := time.Parse("02-01-2006", dp.Date); err == nil && !d.After(cursor) {
+				return true
+			}
+		}
+		if limit > 0 && len(page) >= limit {
+			hasMore = true
+			return false
+		}
+		page = append(page, dp)
+		return true
+	})
+	return page, hasMore
+}
+
+// peekNextPage reports whether more rows remain beyond the current page
+// (strictly after cursor, up to limit) and the date of the last row the
+// page would contain, without buffering the page itself. Streaming writers
+// use this to decide the Link header before any of the body is written.
+func peekNextPage(data []struct {
+	Date string `json:"date"`
+	Nav  string `json:"nav"`
+}, start, end, cursor time.Time, limit int) (lastDate string, hasMore bool) {
+	if limit <= 0 {
+		return "", false
+	}
+
+	count := 0
+	iterateFilteredData(data, start, end)(func(dp DataPoint) bool {
+		if !cursor.IsZero() {
+			if d, err := time.Parse("02-01-2006", dp.Date); err == nil && !d.After(cursor) {
+				return true
+			}
+		}
+		if count >= limit {
+			hasMore = true
+			return false
+		}
+		count++
+		lastDate = dp.Date
+		return true
+	})
+
+	return lastDate, hasMore
+}
+
+// setNextLink sets a Link: <...>; rel="next" header pointing at the next
+// page, reusing the request's query parameters with cursor advanced past
+// the last row of the current page.
+func setNextLink(w http.ResponseWriter, r *http.Request, lastDate string) {
+	q := r.URL.Query()
+	q.Set("cursor", base64.URLEncoding.EncodeToString([]byte(lastDate)))
+	u := *r.URL
+	u.RawQuery = q.Encode()
+	w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, u.String()))
+}
+
+// writeJSON handles format=json, either buffering the full page (the
+// default) or streaming the data array incrementally when stream=true.
+func writeJSON(w http.ResponseWriter, r *http.Request, meta struct {
+	FundHouse      string `json:"fund_house"`
+	SchemeType     string `json:"scheme_type"`
+	SchemeCategory string `json:"scheme_category"`
+	SchemeCode     int    `json:"scheme_code"`
+	SchemeName     string `json:"scheme_name"`
+}, data []struct {
+	Date string `json:"date"`
+	Nav  string `json:"nav"`
+}, start, end, cursor time.Time, limit int, stream bool) {
+	page, hasMore := collectPage(data, start, end, cursor, limit)
+	if hasMore && len(page) > 0 {
+		setNextLink(w, r, page[len(page)-1].Date)
+	}
 
-	// Write the JSON response
 	w.Header().Set("Content-Type", "application/json")
+
+	if !stream {
+		response := createSuccessResponse(meta, page, start, end)
+		jsonResponse, err := json.Marshal(response)
+		if err != nil {
+			createErrorResponse(w, http.StatusInternalServerError, "error creating JSON response")
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write(jsonResponse)
+		return
+	}
+
 	w.WriteHeader(http.StatusOK)
-	w.Write(jsonResponse)
+	flusher, _ := w.(http.Flusher)
+
+	fmt.Fprint(w, `{"meta":`)
+	metaJSON, _ := json.Marshal(meta)
+	w.Write(metaJSON)
+	fmt.Fprint(w, `,"data":[`)
+	enc := json.NewEncoder(w)
+	for i, dp := range page {
+		if i > 0 {
+			fmt.Fprint(w, ",")
+		}
+		enc.Encode(dp)
+		if flusher != nil && i%100 == 0 {
+			flusher.Flush()
+		}
+	}
+	fmt.Fprint(w, "]}")
+	if flusher != nil {
+		flusher.Flush()
+	}
+}
+
+// writeNDJSON streams one JSON-encoded DataPoint per line.
+func writeNDJSON(w http.ResponseWriter, r *http.Request, data []struct {
+	Date string `json:"date"`
+	Nav  string `json:"nav"`
+}, start, end, cursor time.Time, limit int, stream bool) {
+	if lastDate, hasMore := peekNextPage(data, start, end, cursor, limit); hasMore {
+		setNextLink(w, r, lastDate)
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+
+	enc := json.NewEncoder(w)
+	count := 0
+	iterateFilteredData(data, start, end)(func(dp DataPoint) bool {
+		if !cursor.IsZero() {
+			if d, err := time.Parse("02-01-2006", dp.Date); err == nil && !d.After(cursor) {
+				return true
+			}
+		}
+		if limit > 0 && count >= limit {
+			return false
+		}
+		enc.Encode(dp)
+		count++
+		if stream && flusher != nil {
+			flusher.Flush()
+		}
+		return true
+	})
+	if flusher != nil {
+		flusher.Flush()
+	}
+}
+
+// writeCSV streams the filtered rows as CSV with a date,nav header.
+func writeCSV(w http.ResponseWriter, r *http.Request, data []struct {
+	Date string `json:"date"`
+	Nav  string `json:"nav"`
+}, start, end, cursor time.Time, limit int, stream bool) {
+	if lastDate, hasMore := peekNextPage(data, start, end, cursor, limit); hasMore {
+		setNextLink(w, r, lastDate)
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"date", "nav"})
+	if stream {
+		writer.Flush()
+	}
+
+	count := 0
+	iterateFilteredData(data, start, end)(func(dp DataPoint) bool {
+		if !cursor.IsZero() {
+			if d, err := time.Parse("02-01-2006", dp.Date); err == nil && !d.After(cursor) {
+				return true
+			}
+		}
+		if limit > 0 && count >= limit {
+			return false
+		}
+		writer.Write([]string{dp.Date, dp.Nav})
+		count++
+		if stream {
+			writer.Flush()
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		return true
+	})
+	writer.Flush()
+	if flusher != nil {
+		flusher.Flush()
+	}
 }
 
 // isValidInteger checks if a string can be parsed as an integer.
@@ -113,20 +410,46 @@ func isValidInteger(value string) bool {
 	return err == nil
 }
 
+// isEmptyMeta reports whether a Fund's Meta is the zero value, which mfapi.in
+// returns for an unrecognized mutualFundID instead of an HTTP error.
+func isEmptyMeta(meta struct {
+	FundHouse      string `json:"fund_house"`
+	SchemeType     string `json:"scheme_type"`
+	SchemeCategory string `json:"scheme_category"`
+	SchemeCode     int    `json:"scheme_code"`
+	SchemeName     string `json:"scheme_name"`
+}) bool {
+	return meta == (struct {
+		FundHouse      string `json:"fund_house"`
+		SchemeType     string `json:"scheme_type"`
+		SchemeCategory string `json:"scheme_category"`
+		SchemeCode     int    `json:"scheme_code"`
+		SchemeName     string `json:"scheme_name"`
+	}{})
+}
+
 // fetchFundData fetches the fund data from the API using the mutualFundID.
-func fetchFundData(mutualFundID string) (Fund, error) {
+func fetchFundData(ctx context.Context, mutualFundID string) (Fund, error) {
 	var fund Fund
 
 	url := fmt.Sprintf("%s%s", baseURL, mutualFundID)
-	resp, err := http.Get(url)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fund, fmt.Errorf("error building request to API: %w", err)
+	}
+
+	resp, err := upstreamClient.Do(req)
 	if err != nil {
+		observability.UpstreamRequestsTotal.WithLabelValues("error").Inc()
 		return fund, fmt.Errorf("error fetching data from API: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if err := json.NewDecoder(resp.Body).Decode(&fund); err != nil {
+		observability.UpstreamRequestsTotal.WithLabelValues("error").Inc()
 		return fund, fmt.Errorf("error decoding API response: %w", err)
 	}
+	observability.UpstreamRequestsTotal.WithLabelValues("success").Inc()
 
 	return fund, nil
 }
@@ -175,26 +498,6 @@ func parseDates(startDate, endDate string) (time.Time, time.Time, error) {
 	return start, end, nil
 }
 
-// filterData filters the data based on the provided date range.
-func filterData(data []struct {
-	Date string `json:"date"`
-	Nav  string `json:"nav"`
-}, start, end time.Time) []DataPoint {
-	var filteredData []DataPoint
-
-	for _, item := range data {
-		date, err := time.Parse("02-01-2006", item.Date)
-		if err != nil {
-			continue
-		}
-		if (start.IsZero() && end.IsZero()) || (date.Equal(start) || date.After(start)) && (date.Equal(end) || date.Before(end)) {
-			filteredData = append(filteredData, DataPoint{Date: item.Date, Nav: item.Nav})
-		}
-	}
-
-	return filteredData
-}
-
 // createSuccessResponse creates a successful response with the given data and period.
 func createSuccessResponse(meta struct {
 	FundHouse      string `json:"fund_house"`
@@ -0,0 +1,23 @@
+package mutualfunds
+
+import (
+	"net/http"
+	"os"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics serves Prometheus-format metrics for scraping. Like Cron, it is
+// protected by the CRON_SECRET bearer token so it isn't publicly scrapeable
+// without configuring a scraper with the token.
+func Metrics(w http.ResponseWriter, r *http.Request) {
+	authHeader := r.Header.Get("Authorization")
+	cronSecret := os.Getenv("CRON_SECRET")
+
+	if authHeader != "Bearer "+cronSecret {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	promhttp.Handler().ServeHTTP(w, r)
+}
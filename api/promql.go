@@ -0,0 +1,847 @@
+package mutualfunds
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Maximum number of steps a single range query may evaluate. This bounds the
+// evaluation work per query and the size of the response payload; upstream
+// fetches happen once per distinct scheme code regardless of step count.
+const maxQuerySteps = 11000
+
+// QueryHandler evaluates a PromQL-like expression over NAV time series and
+// responds with a payload shaped like Prometheus' HTTP query API
+// (https://prometheus.io/docs/prometheus/latest/querying/api/#range-queries).
+func QueryHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("query")
+	if query == "" {
+		createErrorResponse(w, http.StatusBadRequest, "query parameter is required")
+		return
+	}
+
+	start, end, step, err := parseRangeParams(r.URL.Query().Get("start"), r.URL.Query().Get("end"), r.URL.Query().Get("step"))
+	if err != nil {
+		createErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	expr, err := parseExpr(query)
+	if err != nil {
+		createErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("error parsing query: %s", err))
+		return
+	}
+
+	eng := newQueryEngine()
+	result, err := eng.evalRange(r.Context(), expr, start, end, step)
+	if err != nil {
+		createErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(promQueryResponse{
+		Status: "success",
+		Data:   result,
+	})
+}
+
+// parseRangeParams validates and parses the start/end/step query parameters.
+// start and end accept RFC3339 or unix-seconds timestamps; step accepts a
+// PromQL-style duration (e.g. "1h", "30m") or a number of seconds.
+func parseRangeParams(startParam, endParam, stepParam string) (time.Time, time.Time, time.Duration, error) {
+	if startParam == "" || endParam == "" || stepParam == "" {
+		return time.Time{}, time.Time{}, 0, fmt.Errorf("start, end, and step query parameters are required")
+	}
+
+	start, err := parseTimestamp(startParam)
+	if err != nil {
+		return time.Time{}, time.Time{}, 0, fmt.Errorf("invalid start: %w", err)
+	}
+
+	end, err := parseTimestamp(endParam)
+	if err != nil {
+		return time.Time{}, time.Time{}, 0, fmt.Errorf("invalid end: %w", err)
+	}
+
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, 0, fmt.Errorf("end must be greater than or equal to start")
+	}
+
+	step, err := parseStepDuration(stepParam)
+	if err != nil {
+		return time.Time{}, time.Time{}, 0, fmt.Errorf("invalid step: %w", err)
+	}
+	if step <= 0 {
+		return time.Time{}, time.Time{}, 0, fmt.Errorf("step must be greater than zero")
+	}
+
+	if steps := int(end.Sub(start)/step) + 1; steps > maxQuerySteps {
+		return time.Time{}, time.Time{}, 0, fmt.Errorf("query would evaluate %d steps, exceeding the limit of %d", steps, maxQuerySteps)
+	}
+
+	return start, end, step, nil
+}
+
+func parseTimestamp(value string) (time.Time, error) {
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.Unix(int64(seconds), 0).UTC(), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+func parseStepDuration(value string) (time.Duration, error) {
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+	return time.ParseDuration(value)
+}
+
+// promQueryResponse mirrors Prometheus' top-level query response envelope.
+type promQueryResponse struct {
+	Status string      `json:"status"`
+	Data   queryResult `json:"data"`
+}
+
+type queryResult struct {
+	ResultType string   `json:"resultType"`
+	Result     []series `json:"result"`
+}
+
+type series struct {
+	Metric map[string]string `json:"metric"`
+	Value  *[2]interface{}   `json:"value,omitempty"`
+	Values [][2]interface{}  `json:"values,omitempty"`
+}
+
+// --- Lexer -----------------------------------------------------------------
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenIdent
+	tokenString
+	tokenLeftBrace
+	tokenRightBrace
+	tokenLeftParen
+	tokenRightParen
+	tokenLeftBracket
+	tokenRightBracket
+	tokenComma
+	tokenEquals
+	tokenOperator
+)
+
+type token struct {
+	kind tokenKind
+	val  string
+}
+
+type lexer struct {
+	input string
+	pos   int
+}
+
+func newLexer(input string) *lexer {
+	return &lexer{input: input}
+}
+
+func (l *lexer) peekRune() rune {
+	if l.pos >= len(l.input) {
+		return 0
+	}
+	return rune(l.input[l.pos])
+}
+
+func (l *lexer) next() (token, error) {
+	for l.pos < len(l.input) && l.input[l.pos] == ' ' {
+		l.pos++
+	}
+	if l.pos >= len(l.input) {
+		return token{kind: tokenEOF}, nil
+	}
+
+	c := l.input[l.pos]
+	switch c {
+	case '{':
+		l.pos++
+		return token{kind: tokenLeftBrace, val: "{"}, nil
+	case '}':
+		l.pos++
+		return token{kind: tokenRightBrace, val: "}"}, nil
+	case '(':
+		l.pos++
+		return token{kind: tokenLeftParen, val: "("}, nil
+	case ')':
+		l.pos++
+		return token{kind: tokenRightParen, val: ")"}, nil
+	case '[':
+		l.pos++
+		return token{kind: tokenLeftBracket, val: "["}, nil
+	case ']':
+		l.pos++
+		return token{kind: tokenRightBracket, val: "]"}, nil
+	case ',':
+		l.pos++
+		return token{kind: tokenComma, val: ","}, nil
+	case '=':
+		l.pos++
+		return token{kind: tokenEquals, val: "="}, nil
+	case '+', '-', '*', '/':
+		l.pos++
+		return token{kind: tokenOperator, val: string(c)}, nil
+	case '"':
+		return l.lexString()
+	}
+
+	if isDigit(c) {
+		return l.lexNumber()
+	}
+	if isIdentStart(c) {
+		return l.lexIdent()
+	}
+
+	return token{}, fmt.Errorf("unexpected character %q at position %d", c, l.pos)
+}
+
+func (l *lexer) lexString() (token, error) {
+	start := l.pos
+	l.pos++ // consume opening quote
+	for l.pos < len(l.input) && l.input[l.pos] != '"' {
+		l.pos++
+	}
+	if l.pos >= len(l.input) {
+		return token{}, fmt.Errorf("unterminated string starting at position %d", start)
+	}
+	val := l.input[start+1 : l.pos]
+	l.pos++ // consume closing quote
+	return token{kind: tokenString, val: val}, nil
+}
+
+func (l *lexer) lexNumber() (token, error) {
+	start := l.pos
+	for l.pos < len(l.input) && (isDigit(l.input[l.pos]) || l.input[l.pos] == '.') {
+		l.pos++
+	}
+	return token{kind: tokenNumber, val: l.input[start:l.pos]}, nil
+}
+
+func (l *lexer) lexIdent() (token, error) {
+	start := l.pos
+	for l.pos < len(l.input) && isIdentPart(l.input[l.pos]) {
+		l.pos++
+	}
+	return token{kind: tokenIdent, val: l.input[start:l.pos]}, nil
+}
+
+func isDigit(c byte) bool      { return c >= '0' && c <= '9' }
+func isIdentStart(c byte) bool { return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') }
+func isIdentPart(c byte) bool  { return isIdentStart(c) || isDigit(c) }
+
+// --- AST ---------------------------------------------------------------
+
+type node interface{}
+
+type numberLiteral struct {
+	val float64
+}
+
+type vectorSelector struct {
+	schemeCode string
+}
+
+type matrixSelector struct {
+	inner    *vectorSelector
+	rangeDur time.Duration
+}
+
+type call struct {
+	funcName string
+	args     []node
+}
+
+type binaryExpr struct {
+	op  string
+	lhs node
+	rhs node
+}
+
+// --- Parser (Pratt) ------------------------------------------------------
+
+type parser struct {
+	lex    *lexer
+	cur    token
+	peeked *token
+}
+
+func parseExpr(input string) (node, error) {
+	p := &parser{lex: newLexer(input)}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	expr, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.cur.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected trailing token %q", p.cur.val)
+	}
+	return expr, nil
+}
+
+func (p *parser) advance() error {
+	t, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.cur = t
+	return nil
+}
+
+var precedence = map[string]int{"+": 1, "-": 1, "*": 2, "/": 2}
+
+func (p *parser) parseBinary(minPrec int) (node, error) {
+	lhs, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.cur.kind == tokenOperator {
+		op := p.cur.val
+		prec, ok := precedence[op]
+		if !ok || prec < minPrec {
+			break
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		rhs, err := p.parseBinary(prec + 1)
+		if err != nil {
+			return nil, err
+		}
+		lhs = &binaryExpr{op: op, lhs: lhs, rhs: rhs}
+	}
+
+	return lhs, nil
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	switch p.cur.kind {
+	case tokenNumber:
+		val, err := strconv.ParseFloat(p.cur.val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", p.cur.val)
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		return &numberLiteral{val: val}, nil
+
+	case tokenIdent:
+		name := p.cur.val
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if p.cur.kind == tokenLeftParen {
+			return p.parseCall(name)
+		}
+		return p.parseSelector(name)
+
+	case tokenLeftParen:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		expr, err := p.parseBinary(0)
+		if err != nil {
+			return nil, err
+		}
+		if p.cur.kind != tokenRightParen {
+			return nil, fmt.Errorf("expected closing parenthesis")
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		return expr, nil
+	}
+
+	return nil, fmt.Errorf("unexpected token %q", p.cur.val)
+}
+
+func (p *parser) parseCall(name string) (node, error) {
+	if err := p.advance(); err != nil { // consume '('
+		return nil, err
+	}
+	var args []node
+	for p.cur.kind != tokenRightParen {
+		arg, err := p.parseBinary(0)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		if p.cur.kind == tokenComma {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		break
+	}
+	if p.cur.kind != tokenRightParen {
+		return nil, fmt.Errorf("expected closing parenthesis in call to %s", name)
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	return &call{funcName: name, args: args}, nil
+}
+
+func (p *parser) parseSelector(metricName string) (node, error) {
+	var schemeCode string
+
+	if p.cur.kind == tokenLeftBrace {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		for p.cur.kind != tokenRightBrace {
+			if p.cur.kind != tokenIdent {
+				return nil, fmt.Errorf("expected label name in matcher")
+			}
+			label := p.cur.val
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if p.cur.kind != tokenEquals {
+				return nil, fmt.Errorf("expected '=' after label %s", label)
+			}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if p.cur.kind != tokenString {
+				return nil, fmt.Errorf("expected quoted label value for %s", label)
+			}
+			if label == "scheme_code" {
+				schemeCode = p.cur.val
+			}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if p.cur.kind == tokenComma {
+				if err := p.advance(); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			break
+		}
+		if p.cur.kind != tokenRightBrace {
+			return nil, fmt.Errorf("expected closing brace in selector")
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+
+	if schemeCode == "" {
+		return nil, fmt.Errorf("selector for %q must specify scheme_code", metricName)
+	}
+	sel := &vectorSelector{schemeCode: schemeCode}
+
+	if p.cur.kind == tokenLeftBracket {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if p.cur.kind != tokenNumber && p.cur.kind != tokenIdent {
+			return nil, fmt.Errorf("expected duration inside range selector")
+		}
+		durStr := p.cur.val
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		// durations like "30d" are lexed as number "30" followed by ident "d"
+		if p.cur.kind == tokenIdent {
+			durStr += p.cur.val
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+		if p.cur.kind != tokenRightBracket {
+			return nil, fmt.Errorf("expected closing bracket in range selector")
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		dur, err := parsePromDuration(durStr)
+		if err != nil {
+			return nil, err
+		}
+		return &matrixSelector{inner: sel, rangeDur: dur}, nil
+	}
+
+	return sel, nil
+}
+
+// parsePromDuration parses PromQL-style durations such as "30d", "1h", "15m".
+func parsePromDuration(s string) (time.Duration, error) {
+	if len(s) < 2 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	unit := s[len(s)-1]
+	numPart := s[:len(s)-1]
+	n, err := strconv.ParseFloat(numPart, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	switch unit {
+	case 's':
+		return time.Duration(n * float64(time.Second)), nil
+	case 'm':
+		return time.Duration(n * float64(time.Minute)), nil
+	case 'h':
+		return time.Duration(n * float64(time.Hour)), nil
+	case 'd':
+		return time.Duration(n * 24 * float64(time.Hour)), nil
+	case 'w':
+		return time.Duration(n * 7 * 24 * float64(time.Hour)), nil
+	}
+	return 0, fmt.Errorf("unknown duration unit in %q", s)
+}
+
+// --- Engine --------------------------------------------------------------
+
+type navSample struct {
+	t time.Time
+	v float64
+}
+
+// queryEngine evaluates a parsed expression over a time range, caching NAV
+// series per scheme code so repeated selectors within one query only fetch
+// upstream once.
+type queryEngine struct {
+	seriesCache map[string][]navSample
+	metaCache   map[string]fundMeta
+}
+
+type fundMeta struct {
+	fundHouse      string
+	schemeCategory string
+}
+
+func newQueryEngine() *queryEngine {
+	return &queryEngine{
+		seriesCache: make(map[string][]navSample),
+		metaCache:   make(map[string]fundMeta),
+	}
+}
+
+func (e *queryEngine) loadSeries(ctx context.Context, schemeCode string) ([]navSample, fundMeta, error) {
+	if series, ok := e.seriesCache[schemeCode]; ok {
+		return series, e.metaCache[schemeCode], nil
+	}
+
+	fund, err := getCachedFund(ctx, schemeCode)
+	if err != nil {
+		return nil, fundMeta{}, fmt.Errorf("fetching scheme %s: %w", schemeCode, err)
+	}
+
+	samples := make([]navSample, 0, len(fund.Data))
+	for _, point := range fund.Data {
+		t, err := time.Parse("02-01-2006", point.Date)
+		if err != nil {
+			continue
+		}
+		v, err := strconv.ParseFloat(point.Nav, 64)
+		if err != nil {
+			continue
+		}
+		samples = append(samples, navSample{t: t, v: v})
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i].t.Before(samples[j].t) })
+
+	meta := fundMeta{fundHouse: fund.Meta.FundHouse, schemeCategory: fund.Meta.SchemeCategory}
+	e.seriesCache[schemeCode] = samples
+	e.metaCache[schemeCode] = meta
+	return samples, meta, nil
+}
+
+// instantVector is the result of evaluating a node at a single timestamp: one
+// float value per scheme code.
+type instantVector map[string]float64
+
+func (e *queryEngine) evalRange(ctx context.Context, n node, start, end time.Time, step time.Duration) (queryResult, error) {
+	schemeCodes, err := selectorSchemeCodes(n)
+	if err != nil {
+		return queryResult{}, err
+	}
+	for _, code := range schemeCodes {
+		if _, _, err := e.loadSeries(ctx, code); err != nil {
+			return queryResult{}, err
+		}
+	}
+
+	timestamps := make([]time.Time, 0, maxQuerySteps)
+	for t := start; !t.After(end); t = t.Add(step) {
+		timestamps = append(timestamps, t)
+	}
+	if len(timestamps) == 0 {
+		timestamps = append(timestamps, start)
+	}
+
+	values := make(map[string][][2]interface{})
+	for _, t := range timestamps {
+		vec, err := e.evalInstant(n, t)
+		if err != nil {
+			return queryResult{}, err
+		}
+		for code, v := range vec {
+			values[code] = append(values[code], [2]interface{}{float64(t.Unix()), strconv.FormatFloat(v, 'f', -1, 64)})
+		}
+	}
+
+	resultType := "matrix"
+	if start.Equal(end) {
+		resultType = "vector"
+	}
+
+	result := make([]series, 0, len(values))
+	for code, points := range values {
+		meta := e.metaCache[code]
+		s := series{
+			Metric: map[string]string{
+				"scheme_code":     code,
+				"fund_house":      meta.fundHouse,
+				"scheme_category": meta.schemeCategory,
+			},
+		}
+		if resultType == "vector" {
+			if len(points) > 0 {
+				point := points[0]
+				s.Value = &point
+			}
+		} else {
+			s.Values = points
+		}
+		result = append(result, s)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Metric["scheme_code"] < result[j].Metric["scheme_code"] })
+
+	return queryResult{ResultType: resultType, Result: result}, nil
+}
+
+// selectorSchemeCodes walks the AST collecting the distinct scheme codes
+// referenced, so the engine can warm the series cache before evaluation.
+func selectorSchemeCodes(n node) ([]string, error) {
+	seen := make(map[string]bool)
+	var walk func(node) error
+	walk = func(n node) error {
+		switch v := n.(type) {
+		case *numberLiteral:
+			return nil
+		case *vectorSelector:
+			seen[v.schemeCode] = true
+		case *matrixSelector:
+			seen[v.inner.schemeCode] = true
+		case *call:
+			for _, arg := range v.args {
+				if err := walk(arg); err != nil {
+					return err
+				}
+			}
+		case *binaryExpr:
+			if err := walk(v.lhs); err != nil {
+				return err
+			}
+			return walk(v.rhs)
+		default:
+			return fmt.Errorf("unsupported expression node %T", n)
+		}
+		return nil
+	}
+	if err := walk(n); err != nil {
+		return nil, err
+	}
+	codes := make([]string, 0, len(seen))
+	for code := range seen {
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+func (e *queryEngine) evalInstant(n node, t time.Time) (instantVector, error) {
+	switch v := n.(type) {
+	case *numberLiteral:
+		return instantVector{"": v.val}, nil
+
+	case *vectorSelector:
+		series := e.seriesCache[v.schemeCode]
+		point, ok := latestAt(series, t)
+		if !ok {
+			return instantVector{}, nil
+		}
+		return instantVector{v.schemeCode: point.v}, nil
+
+	case *matrixSelector:
+		return nil, fmt.Errorf("range vector selector used where instant vector expected")
+
+	case *call:
+		return e.evalCall(v, t)
+
+	case *binaryExpr:
+		lhs, err := e.evalInstant(v.lhs, t)
+		if err != nil {
+			return nil, err
+		}
+		rhs, err := e.evalInstant(v.rhs, t)
+		if err != nil {
+			return nil, err
+		}
+		return applyBinaryOp(v.op, lhs, rhs)
+	}
+
+	return nil, fmt.Errorf("unsupported expression node %T", n)
+}
+
+func (e *queryEngine) evalCall(c *call, t time.Time) (instantVector, error) {
+	if len(c.args) != 1 {
+		return nil, fmt.Errorf("%s: expected exactly one argument", c.funcName)
+	}
+	sel, ok := c.args[0].(*matrixSelector)
+	if !ok {
+		return nil, fmt.Errorf("%s: expected a range vector argument", c.funcName)
+	}
+
+	series := e.seriesCache[sel.inner.schemeCode]
+	window := windowed(series, t.Add(-sel.rangeDur), t)
+	if len(window) == 0 {
+		return instantVector{}, nil
+	}
+
+	var result float64
+	switch c.funcName {
+	case "rate", "delta":
+		first, last := window[0], window[len(window)-1]
+		diff := last.v - first.v
+		if c.funcName == "rate" {
+			elapsed := last.t.Sub(first.t).Seconds()
+			if elapsed == 0 {
+				return instantVector{}, nil
+			}
+			result = diff / elapsed
+		} else {
+			result = diff
+		}
+	case "avg_over_time":
+		var sum float64
+		for _, s := range window {
+			sum += s.v
+		}
+		result = sum / float64(len(window))
+	case "stddev_over_time":
+		var sum float64
+		for _, s := range window {
+			sum += s.v
+		}
+		mean := sum / float64(len(window))
+		var variance float64
+		for _, s := range window {
+			variance += (s.v - mean) * (s.v - mean)
+		}
+		result = math.Sqrt(variance / float64(len(window)))
+	default:
+		return nil, fmt.Errorf("unknown function %q", c.funcName)
+	}
+
+	return instantVector{sel.inner.schemeCode: result}, nil
+}
+
+func applyBinaryOp(op string, lhs, rhs instantVector) (instantVector, error) {
+	result := make(instantVector)
+
+	// Scalar-to-vector: a number literal carries the sentinel "" key.
+	if scalar, ok := lhs[""]; ok && len(lhs) == 1 {
+		for code, v := range rhs {
+			result[code] = applyOp(op, scalar, v)
+		}
+		return result, nil
+	}
+	if scalar, ok := rhs[""]; ok && len(rhs) == 1 {
+		for code, v := range lhs {
+			result[code] = applyOp(op, v, scalar)
+		}
+		return result, nil
+	}
+
+	// Single-series operands on both sides - the common case for cross-fund
+	// arithmetic like `nav{scheme_code="A"} - nav{scheme_code="B"}` - are
+	// matched positionally, ignoring scheme_code, mirroring Prometheus'
+	// `ignoring(scheme_code)` semantics. Matching strictly on scheme_code
+	// here would never find a match since the two sides reference different
+	// schemes by definition.
+	if len(lhs) == 1 && len(rhs) == 1 {
+		for lhsCode, lv := range lhs {
+			for _, rv := range rhs {
+				result[lhsCode] = applyOp(op, lv, rv)
+			}
+		}
+		return result, nil
+	}
+
+	// Multi-series vectors: match samples by scheme_code.
+	for code, lv := range lhs {
+		if rv, ok := rhs[code]; ok {
+			result[code] = applyOp(op, lv, rv)
+		}
+	}
+	return result, nil
+}
+
+func applyOp(op string, lhs, rhs float64) float64 {
+	switch op {
+	case "+":
+		return lhs + rhs
+	case "-":
+		return lhs - rhs
+	case "*":
+		return lhs * rhs
+	case "/":
+		return lhs / rhs
+	}
+	return 0
+}
+
+func latestAt(series []navSample, t time.Time) (navSample, bool) {
+	var latest navSample
+	found := false
+	for _, s := range series {
+		if s.t.After(t) {
+			break
+		}
+		latest = s
+		found = true
+	}
+	return latest, found
+}
+
+func windowed(series []navSample, from, to time.Time) []navSample {
+	var window []navSample
+	for _, s := range series {
+		if s.t.Before(from) {
+			continue
+		}
+		if s.t.After(to) {
+			break
+		}
+		window = append(window, s)
+	}
+	return window
+}
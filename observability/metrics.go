@@ -0,0 +1,39 @@
+// Package observability provides shared Prometheus metrics and structured
+// request logging for the project's HTTP handlers.
+package observability
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	// HandlerDuration records how long each route took to respond, labeled
+	// by route and final HTTP status.
+	HandlerDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "mf_handler_duration_seconds",
+		Help:    "Duration of HTTP handler invocations in seconds.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "status"})
+
+	// InFlightRequests tracks the number of requests currently being served.
+	InFlightRequests = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "mf_in_flight_requests",
+		Help: "Number of HTTP requests currently being handled.",
+	})
+
+	// UpstreamRequestsTotal counts calls made to the upstream mfapi.in API,
+	// labeled by outcome ("success" or "error").
+	UpstreamRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "mf_upstream_requests_total",
+		Help: "Total number of requests made to the upstream mfapi.in API.",
+	}, []string{"result"})
+
+	// CacheEventsTotal counts cache lookups, labeled by event ("hit",
+	// "miss", or "stale_serve").
+	CacheEventsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "mf_cache_events_total",
+		Help: "Total number of NAV cache lookups by event type.",
+	}, []string{"event"})
+)
+
+func init() {
+	prometheus.MustRegister(HandlerDuration, InFlightRequests, UpstreamRequestsTotal, CacheEventsTotal)
+}
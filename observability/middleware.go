@@ -0,0 +1,83 @@
+package observability
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+)
+
+// requestLogger writes one JSON object per line to stderr.
+var requestLogger = slog.New(slog.NewJSONHandler(os.Stderr, nil))
+
+// statusRecorder captures the status code written by the wrapped handler so
+// it can be reported to both the metrics and the log line.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// WithObservability wraps an HTTP handler with request metrics and one
+// structured JSON log line per request, generating (or propagating) an
+// X-Request-ID header along the way.
+func WithObservability(route string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get("X-Request-ID")
+		if requestID == "" {
+			requestID = newRequestID()
+		}
+		w.Header().Set("X-Request-ID", requestID)
+
+		InFlightRequests.Inc()
+		defer InFlightRequests.Dec()
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+
+		next(recorder, r)
+
+		duration := time.Since(start)
+		status := recorder.status
+
+		HandlerDuration.WithLabelValues(route, statusLabel(status)).Observe(duration.Seconds())
+
+		requestLogger.Info("request",
+			"ts", start.UTC().Format(time.RFC3339),
+			"route", route,
+			"status", status,
+			"duration_ms", duration.Milliseconds(),
+			"mutualFundID", r.URL.Query().Get("mutualFundID"),
+			"remote_ip", remoteIP(r),
+			"request_id", requestID,
+		)
+	}
+}
+
+func statusLabel(status int) string {
+	if status == 0 {
+		status = http.StatusOK
+	}
+	return http.StatusText(status)
+}
+
+func remoteIP(r *http.Request) string {
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		return forwarded
+	}
+	return r.RemoteAddr
+}
+
+func newRequestID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(buf)
+}